@@ -0,0 +1,449 @@
+// Package anthropic adapts Anthropic's Messages API to the
+// [provider.Client] interface.
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/yukinagae/genkit-golang-openai-sample/internal/provider"
+)
+
+const (
+	defaultBaseURL   = "https://api.anthropic.com/v1"
+	anthropicVersion = "2023-06-01"
+)
+
+// finishReasons maps Anthropic's stop_reason values to the Genkit equivalent.
+var finishReasons = provider.FinishReasonMap{
+	"end_turn":      ai.FinishReasonStop,
+	"stop_sequence": ai.FinishReasonStop,
+	"tool_use":      ai.FinishReasonStop,
+	"max_tokens":    ai.FinishReasonLength,
+}
+
+// Client calls the Anthropic Messages API.
+type Client struct {
+	apiKey  string
+	baseURL string
+	http    *http.Client
+}
+
+// New returns a Client for the given API key. baseURL may be empty to use
+// the default Anthropic endpoint.
+func New(apiKey, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{apiKey: apiKey, baseURL: baseURL, http: http.DefaultClient}
+}
+
+type message struct {
+	Role    string    `json:"role"`
+	Content []content `json:"content"`
+}
+
+type content struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	Source    *imageSource    `json:"source,omitempty"`
+}
+
+// imageSource is an Anthropic "image" content block's source: either base64
+// data with its media type, or a URL.
+type imageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type chatRequest struct {
+	Model         string    `json:"model"`
+	System        string    `json:"system,omitempty"`
+	Messages      []message `json:"messages"`
+	Tools         []tool    `json:"tools,omitempty"`
+	MaxTokens     int       `json:"max_tokens"`
+	Temperature   float32   `json:"temperature,omitempty"`
+	TopP          float32   `json:"top_p,omitempty"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+	Stream        bool      `json:"stream,omitempty"`
+}
+
+type chatResponse struct {
+	Content    []content `json:"content"`
+	StopReason string    `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// Generate implements [provider.Client].
+func (c *Client) Generate(ctx context.Context, model string, input *ai.GenerateRequest) (*ai.GenerateResponse, error) {
+	req, err := convertRequest(model, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp chatResponse
+	if err := c.do(ctx, "/messages", req, &resp); err != nil {
+		return nil, err
+	}
+
+	r := translateResponse(resp)
+	r.Request = input
+	return r, nil
+}
+
+// Stream implements [provider.Client] by consuming Anthropic's
+// server-sent-events stream, forwarding each text delta to cb as it arrives,
+// and returning the final response synthesized once the stream closes.
+func (c *Client) Stream(ctx context.Context, model string, input *ai.GenerateRequest, cb func(context.Context, *ai.GenerateResponseChunk) error) (*ai.GenerateResponse, error) {
+	req, err := convertRequest(model, input)
+	if err != nil {
+		return nil, err
+	}
+	req.Stream = true
+
+	body, err := c.doStream(ctx, "/messages", req)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	blocks := map[int]*streamBlock{}
+	var order []int
+	var stopReason string
+	var usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+
+		var ev streamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return nil, fmt.Errorf("anthropic: decoding stream event: %w", err)
+		}
+
+		switch ev.Type {
+		case "message_start":
+			usage.InputTokens = ev.Message.Usage.InputTokens
+		case "content_block_start":
+			blocks[ev.Index] = &streamBlock{typ: ev.ContentBlock.Type, name: ev.ContentBlock.Name, id: ev.ContentBlock.ID}
+			order = append(order, ev.Index)
+		case "content_block_delta":
+			b := blocks[ev.Index]
+			switch ev.Delta.Type {
+			case "text_delta":
+				b.text.WriteString(ev.Delta.Text)
+				if err := cb(ctx, &ai.GenerateResponseChunk{
+					Index:   ev.Index,
+					Content: []*ai.Part{ai.NewTextPart(ev.Delta.Text)},
+				}); err != nil {
+					return nil, err
+				}
+			case "input_json_delta":
+				b.input.WriteString(ev.Delta.PartialJSON)
+			}
+		case "message_delta":
+			if ev.Delta.StopReason != "" {
+				stopReason = ev.Delta.StopReason
+			}
+			usage.OutputTokens = ev.Usage.OutputTokens
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("anthropic: reading stream: %w", err)
+	}
+
+	m := &ai.Message{Role: ai.RoleModel}
+	for _, idx := range order {
+		b := blocks[idx]
+		switch b.typ {
+		case "text":
+			m.Content = append(m.Content, ai.NewTextPart(b.text.String()))
+		case "tool_use":
+			var in map[string]any
+			if b.input.Len() > 0 {
+				if err := json.Unmarshal([]byte(b.input.String()), &in); err != nil {
+					return nil, fmt.Errorf("anthropic: decoding tool_use input: %w", err)
+				}
+			}
+			m.Content = append(m.Content, ai.NewToolRequestPart(&ai.ToolRequest{
+				Ref:   b.id,
+				Name:  b.name,
+				Input: in,
+			}))
+		}
+	}
+
+	r := &ai.GenerateResponse{
+		Candidates: []*ai.Candidate{{
+			Index:        0,
+			FinishReason: finishReasons.Lookup(stopReason),
+			Message:      m,
+		}},
+		Usage: provider.Usage{
+			InputTokens:  usage.InputTokens,
+			OutputTokens: usage.OutputTokens,
+			TotalTokens:  usage.InputTokens + usage.OutputTokens,
+		}.ToAI(),
+		Request: input,
+	}
+	return r, nil
+}
+
+// streamBlock accumulates one content block's deltas over the lifetime of a
+// stream, so it can be translated into an ai.Part once the block closes.
+type streamBlock struct {
+	typ   string
+	name  string
+	id    string
+	text  strings.Builder
+	input strings.Builder
+}
+
+// streamEvent is the union of fields used across the Anthropic streaming
+// event types (message_start, content_block_start, content_block_delta,
+// message_delta); fields unused by a given event type are left zero.
+type streamEvent struct {
+	Type         string `json:"type"`
+	Index        int    `json:"index"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
+	Delta struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+func (c *Client) do(ctx context.Context, path string, body, out any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("anthropic: %s: %s", resp.Status, data)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// doStream issues a streaming request and returns the response body for the
+// caller to read as server-sent events. The caller must close it.
+func (c *Client) doStream(ctx context.Context, path string, body any) (io.ReadCloser, error) {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("anthropic: %s: %s", resp.Status, data)
+	}
+	return resp.Body, nil
+}
+
+func convertRequest(model string, input *ai.GenerateRequest) (*chatRequest, error) {
+	req := &chatRequest{Model: model, MaxTokens: 4096}
+
+	if c, ok := input.Config.(*ai.GenerationCommonConfig); ok && c != nil {
+		if c.MaxOutputTokens != 0 {
+			req.MaxTokens = c.MaxOutputTokens
+		}
+		req.Temperature = float32(c.Temperature)
+		req.TopP = float32(c.TopP)
+		req.StopSequences = c.StopSequences
+	}
+
+	for _, m := range input.Messages {
+		if m.Role == ai.RoleSystem {
+			if len(m.Content) > 0 {
+				req.System = m.Content[0].Text
+			}
+			continue
+		}
+		msg, err := convertMessage(m)
+		if err != nil {
+			return nil, err
+		}
+		req.Messages = append(req.Messages, msg)
+	}
+
+	for _, t := range input.Tools {
+		schema, err := json.Marshal(t.InputSchema)
+		if err != nil {
+			return nil, err
+		}
+		req.Tools = append(req.Tools, tool{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: schema,
+		})
+	}
+
+	return req, nil
+}
+
+func convertMessage(m *ai.Message) (message, error) {
+	role := "user"
+	if m.Role == ai.RoleModel {
+		role = "assistant"
+	}
+
+	msg := message{Role: role}
+	for _, part := range m.Content {
+		switch {
+		case part.IsText():
+			msg.Content = append(msg.Content, content{Type: "text", Text: part.Text})
+		case part.IsToolRequest():
+			input, err := json.Marshal(part.ToolRequest.Input)
+			if err != nil {
+				return message{}, err
+			}
+			msg.Content = append(msg.Content, content{
+				Type:  "tool_use",
+				ID:    part.ToolRequest.Ref,
+				Name:  part.ToolRequest.Name,
+				Input: input,
+			})
+		case part.IsToolResponse():
+			output, err := json.Marshal(part.ToolResponse.Output)
+			if err != nil {
+				return message{}, err
+			}
+			msg.Content = append(msg.Content, content{
+				Type:      "tool_result",
+				ToolUseID: part.ToolResponse.Ref,
+				Content:   string(output),
+			})
+		case part.IsMedia():
+			msg.Content = append(msg.Content, content{
+				Type:   "image",
+				Source: convertImageSource(part.ContentType, part.Text),
+			})
+		default:
+			return message{}, fmt.Errorf("anthropic: unsupported part type in a request")
+		}
+	}
+	return msg, nil
+}
+
+// convertImageSource builds an Anthropic image content block's source from a
+// media part's content type and URL/data-URI text. A base64 "data:" URI is
+// split into Anthropic's inline base64 form; anything else, including a
+// "data:" URI without ";base64,", is passed through as a URL source.
+func convertImageSource(contentType, uri string) *imageSource {
+	if data, ok := strings.CutPrefix(uri, "data:"); ok {
+		mediaType, b64Data, found := strings.Cut(data, ";base64,")
+		if !found {
+			return &imageSource{Type: "url", URL: uri}
+		}
+		if contentType == "" {
+			contentType = mediaType
+		}
+		return &imageSource{Type: "base64", MediaType: contentType, Data: b64Data}
+	}
+	return &imageSource{Type: "url", URL: uri}
+}
+
+func translateResponse(resp chatResponse) *ai.GenerateResponse {
+	m := &ai.Message{Role: ai.RoleModel}
+	for _, c := range resp.Content {
+		switch c.Type {
+		case "text":
+			m.Content = append(m.Content, ai.NewTextPart(c.Text))
+		case "tool_use":
+			var in map[string]any
+			_ = json.Unmarshal(c.Input, &in)
+			m.Content = append(m.Content, ai.NewToolRequestPart(&ai.ToolRequest{
+				Ref:   c.ID,
+				Name:  c.Name,
+				Input: in,
+			}))
+		}
+	}
+
+	return &ai.GenerateResponse{
+		Candidates: []*ai.Candidate{{
+			Index:        0,
+			FinishReason: finishReasons.Lookup(resp.StopReason),
+			Message:      m,
+		}},
+		Usage: provider.Usage{
+			InputTokens:  resp.Usage.InputTokens,
+			OutputTokens: resp.Usage.OutputTokens,
+			TotalTokens:  resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		}.ToAI(),
+	}
+}
@@ -0,0 +1,412 @@
+// Package google adapts Gemini's generateContent API to the
+// [provider.Client] interface.
+package google
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/yukinagae/genkit-golang-openai-sample/internal/provider"
+)
+
+const defaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// finishReasons maps Gemini's finishReason values to the Genkit equivalent.
+var finishReasons = provider.FinishReasonMap{
+	"STOP":       ai.FinishReasonStop,
+	"MAX_TOKENS": ai.FinishReasonLength,
+	"SAFETY":     ai.FinishReasonBlocked,
+	"RECITATION": ai.FinishReasonBlocked,
+	"OTHER":      ai.FinishReasonOther,
+}
+
+// Client calls the Gemini generateContent API.
+type Client struct {
+	apiKey  string
+	baseURL string
+	http    *http.Client
+}
+
+// New returns a Client for the given API key. baseURL may be empty to use
+// the default Gemini endpoint.
+func New(apiKey, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{apiKey: apiKey, baseURL: baseURL, http: http.DefaultClient}
+}
+
+type functionCall struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args"`
+}
+
+type functionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+type part struct {
+	Text             string            `json:"text,omitempty"`
+	FunctionCall     *functionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *functionResponse `json:"functionResponse,omitempty"`
+	InlineData       *blob             `json:"inlineData,omitempty"`
+	FileData         *fileData         `json:"fileData,omitempty"`
+}
+
+// blob is inline, base64-encoded media data.
+type blob struct {
+	MIMEType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+// fileData references media hosted at a URL rather than inlined.
+type fileData struct {
+	MIMEType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+type content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []part `json:"parts"`
+}
+
+type functionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type toolDecl struct {
+	FunctionDeclarations []functionDeclaration `json:"functionDeclarations"`
+}
+
+type generationConfig struct {
+	Temperature     float32  `json:"temperature,omitempty"`
+	TopP            float32  `json:"topP,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+}
+
+type generateRequest struct {
+	Contents          []content         `json:"contents"`
+	SystemInstruction *content          `json:"systemInstruction,omitempty"`
+	Tools             []toolDecl        `json:"tools,omitempty"`
+	GenerationConfig  *generationConfig `json:"generationConfig,omitempty"`
+}
+
+type candidate struct {
+	Content      content `json:"content"`
+	FinishReason string  `json:"finishReason"`
+	Index        int     `json:"index"`
+}
+
+type usageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+type generateResponse struct {
+	Candidates    []candidate   `json:"candidates"`
+	UsageMetadata usageMetadata `json:"usageMetadata"`
+}
+
+// Generate implements [provider.Client].
+func (c *Client) Generate(ctx context.Context, model string, input *ai.GenerateRequest) (*ai.GenerateResponse, error) {
+	req, err := convertRequest(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp generateResponse
+	path := fmt.Sprintf("/models/%s:generateContent?key=%s", model, c.apiKey)
+	if err := c.do(ctx, path, req, &resp); err != nil {
+		return nil, err
+	}
+
+	r := translateResponse(resp)
+	r.Request = input
+	return r, nil
+}
+
+// Stream implements [provider.Client] by consuming Gemini's
+// streamGenerateContent endpoint as server-sent events, forwarding each text
+// delta to cb as it arrives, and returning the final response synthesized
+// once the stream closes.
+func (c *Client) Stream(ctx context.Context, model string, input *ai.GenerateRequest, cb func(context.Context, *ai.GenerateResponseChunk) error) (*ai.GenerateResponse, error) {
+	req, err := convertRequest(input)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/models/%s:streamGenerateContent?alt=sse&key=%s", model, c.apiKey)
+	body, err := c.doStream(ctx, path, req)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var texts map[int]*strings.Builder
+	var order []int
+	var finishReason string
+	var usage usageMetadata
+	var toolRequests map[int][]*ai.Part
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+
+		var chunk generateResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("google: decoding stream chunk: %w", err)
+		}
+		usage = chunk.UsageMetadata
+
+		for _, gc := range chunk.Candidates {
+			if texts == nil {
+				texts = map[int]*strings.Builder{}
+			}
+			if toolRequests == nil {
+				toolRequests = map[int][]*ai.Part{}
+			}
+			if _, ok := texts[gc.Index]; !ok {
+				texts[gc.Index] = &strings.Builder{}
+				order = append(order, gc.Index)
+			}
+			if gc.FinishReason != "" {
+				finishReason = gc.FinishReason
+			}
+
+			for _, p := range gc.Content.Parts {
+				switch {
+				case p.FunctionCall != nil:
+					toolRequests[gc.Index] = append(toolRequests[gc.Index], ai.NewToolRequestPart(&ai.ToolRequest{
+						Name:  p.FunctionCall.Name,
+						Input: p.FunctionCall.Args,
+					}))
+				case p.Text != "":
+					texts[gc.Index].WriteString(p.Text)
+					if err := cb(ctx, &ai.GenerateResponseChunk{
+						Index:   gc.Index,
+						Content: []*ai.Part{ai.NewTextPart(p.Text)},
+					}); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("google: reading stream: %w", err)
+	}
+
+	r := &ai.GenerateResponse{
+		Usage: provider.Usage{
+			InputTokens:  usage.PromptTokenCount,
+			OutputTokens: usage.CandidatesTokenCount,
+			TotalTokens:  usage.TotalTokenCount,
+		}.ToAI(),
+		Request: input,
+	}
+	for _, idx := range order {
+		m := &ai.Message{Role: ai.RoleModel}
+		m.Content = append(m.Content, toolRequests[idx]...)
+		if texts[idx].Len() > 0 {
+			m.Content = append(m.Content, ai.NewTextPart(texts[idx].String()))
+		}
+		r.Candidates = append(r.Candidates, &ai.Candidate{
+			Index:        idx,
+			FinishReason: finishReasons.Lookup(finishReason),
+			Message:      m,
+		})
+	}
+	return r, nil
+}
+
+func (c *Client) do(ctx context.Context, path string, body, out any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("google: %s: %s", resp.Status, data)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// doStream issues a streaming request and returns the response body for the
+// caller to read as server-sent events. The caller must close it.
+func (c *Client) doStream(ctx context.Context, path string, body any) (io.ReadCloser, error) {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("google: %s: %s", resp.Status, data)
+	}
+	return resp.Body, nil
+}
+
+func convertRequest(input *ai.GenerateRequest) (*generateRequest, error) {
+	req := &generateRequest{}
+
+	for _, m := range input.Messages {
+		if m.Role == ai.RoleSystem {
+			if len(m.Content) > 0 {
+				req.SystemInstruction = &content{Parts: []part{{Text: m.Content[0].Text}}}
+			}
+			continue
+		}
+		c, err := convertContent(m)
+		if err != nil {
+			return nil, err
+		}
+		req.Contents = append(req.Contents, c)
+	}
+
+	for _, t := range input.Tools {
+		schema, err := json.Marshal(t.InputSchema)
+		if err != nil {
+			return nil, err
+		}
+		if len(req.Tools) == 0 {
+			req.Tools = []toolDecl{{}}
+		}
+		req.Tools[0].FunctionDeclarations = append(req.Tools[0].FunctionDeclarations, functionDeclaration{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  schema,
+		})
+	}
+
+	if c, ok := input.Config.(*ai.GenerationCommonConfig); ok && c != nil {
+		req.GenerationConfig = &generationConfig{
+			Temperature:     float32(c.Temperature),
+			TopP:            float32(c.TopP),
+			MaxOutputTokens: c.MaxOutputTokens,
+			StopSequences:   c.StopSequences,
+		}
+	}
+
+	return req, nil
+}
+
+func convertContent(m *ai.Message) (content, error) {
+	role := "user"
+	if m.Role == ai.RoleModel {
+		role = "model"
+	}
+
+	c := content{Role: role}
+	for _, p := range m.Content {
+		switch {
+		case p.IsText():
+			c.Parts = append(c.Parts, part{Text: p.Text})
+		case p.IsToolRequest():
+			c.Parts = append(c.Parts, part{FunctionCall: &functionCall{
+				Name: p.ToolRequest.Name,
+				Args: p.ToolRequest.Input,
+			}})
+		case p.IsToolResponse():
+			c.Parts = append(c.Parts, part{FunctionResponse: &functionResponse{
+				Name:     p.ToolResponse.Name,
+				Response: p.ToolResponse.Output,
+			}})
+		case p.IsMedia():
+			c.Parts = append(c.Parts, convertMediaPart(p.ContentType, p.Text))
+		default:
+			return content{}, fmt.Errorf("google: unsupported part type in a request")
+		}
+	}
+	return c, nil
+}
+
+// convertMediaPart builds a Gemini media part from a media part's content
+// type and URL/data-URI text. A base64 "data:" URI is split into Gemini's
+// inline base64 form; anything else, including a "data:" URI without
+// ";base64,", is passed through as a fileData reference.
+func convertMediaPart(contentType, uri string) part {
+	if data, ok := strings.CutPrefix(uri, "data:"); ok {
+		mimeType, b64Data, found := strings.Cut(data, ";base64,")
+		if !found {
+			return part{FileData: &fileData{MIMEType: contentType, FileURI: uri}}
+		}
+		if contentType == "" {
+			contentType = mimeType
+		}
+		return part{InlineData: &blob{MIMEType: contentType, Data: b64Data}}
+	}
+	return part{FileData: &fileData{MIMEType: contentType, FileURI: uri}}
+}
+
+func translateResponse(resp generateResponse) *ai.GenerateResponse {
+	r := &ai.GenerateResponse{
+		Usage: provider.Usage{
+			InputTokens:  resp.UsageMetadata.PromptTokenCount,
+			OutputTokens: resp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:  resp.UsageMetadata.TotalTokenCount,
+		}.ToAI(),
+	}
+	for _, gc := range resp.Candidates {
+		m := &ai.Message{Role: ai.RoleModel}
+		for _, p := range gc.Content.Parts {
+			switch {
+			case p.FunctionCall != nil:
+				m.Content = append(m.Content, ai.NewToolRequestPart(&ai.ToolRequest{
+					Name:  p.FunctionCall.Name,
+					Input: p.FunctionCall.Args,
+				}))
+			case p.Text != "":
+				m.Content = append(m.Content, ai.NewTextPart(p.Text))
+			}
+		}
+		r.Candidates = append(r.Candidates, &ai.Candidate{
+			Index:        gc.Index,
+			FinishReason: finishReasons.Lookup(gc.FinishReason),
+			Message:      m,
+		})
+	}
+	return r
+}
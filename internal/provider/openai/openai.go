@@ -0,0 +1,498 @@
+// Package openai adapts the OpenAI chat-completions API to the
+// [provider.Client] interface, so this vendor can be driven by the same
+// plugin surface as Anthropic and Google Gemini.
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/firebase/genkit/go/ai"
+	goopenai "github.com/sashabaranov/go-openai"
+)
+
+// modelsSupportingResponseFormats lists the models that accept the
+// ResponseFormat field (JSON mode / JSON schema, or explicit text).
+var modelsSupportingResponseFormats = []string{
+	goopenai.GPT4o,     //
+	goopenai.GPT4oMini, //
+	goopenai.GPT4Turbo, //
+}
+
+// Client calls the OpenAI chat-completions API.
+type Client struct {
+	raw *goopenai.Client
+}
+
+// New returns a Client that issues chat completions through raw.
+func New(raw *goopenai.Client) *Client {
+	return &Client{raw: raw}
+}
+
+// Generate implements [provider.Client].
+func (c *Client) Generate(ctx context.Context, model string, input *ai.GenerateRequest) (*ai.GenerateResponse, error) {
+	req, err := convertRequest(model, input)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonMode := false
+	if input.Output != nil &&
+		input.Output.Format == ai.OutputFormatJSON {
+		jsonMode = true
+	}
+
+	resp, err := c.raw.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r := translateResponse(resp, jsonMode)
+	r.Request = input
+	return r, nil
+}
+
+// Stream implements [provider.Client].
+func (c *Client) Stream(ctx context.Context, model string, input *ai.GenerateRequest, cb func(context.Context, *ai.GenerateResponseChunk) error) (*ai.GenerateResponse, error) {
+	req, err := convertRequest(model, input)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonMode := false
+	if input.Output != nil &&
+		input.Output.Format == ai.OutputFormatJSON {
+		jsonMode = true
+	}
+
+	return c.generateStream(ctx, req, input, jsonMode, cb)
+}
+
+// generateStream runs req as a server-sent-events stream, forwarding each
+// text delta to cb as it arrives, and returns the final response synthesized
+// by merging all deltas once the stream closes. Tool-call deltas are not
+// forwarded to cb; they appear only as tool requests in the final response.
+func (c *Client) generateStream(
+	ctx context.Context,
+	req goopenai.ChatCompletionRequest,
+	input *ai.GenerateRequest,
+	jsonMode bool,
+	cb func(context.Context, *ai.GenerateResponseChunk) error,
+) (*ai.GenerateResponse, error) {
+	req.Stream = true
+	// OpenAI only includes a usage frame in the stream when asked for one.
+	req.StreamOptions = &goopenai.StreamOptions{IncludeUsage: true}
+
+	stream, err := c.raw.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var order []int
+	candidates := map[int]*streamCandidate{}
+	var usage goopenai.Usage
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+
+		for _, choice := range chunk.Choices {
+			acc, ok := candidates[choice.Index]
+			if !ok {
+				acc = &streamCandidate{toolCalls: map[int]*goopenai.ToolCall{}}
+				candidates[choice.Index] = acc
+				order = append(order, choice.Index)
+			}
+			acc.addDelta(choice)
+
+			if choice.Delta.Content == "" {
+				continue
+			}
+			chunkErr := cb(ctx, &ai.GenerateResponseChunk{
+				Index:   choice.Index,
+				Content: []*ai.Part{ai.NewTextPart(choice.Delta.Content)},
+			})
+			if chunkErr != nil {
+				return nil, chunkErr
+			}
+		}
+	}
+
+	r := &ai.GenerateResponse{
+		Usage: &ai.GenerationUsage{
+			InputTokens:  usage.PromptTokens,
+			OutputTokens: usage.CompletionTokens,
+			TotalTokens:  usage.TotalTokens,
+		},
+	}
+	for _, idx := range order {
+		r.Candidates = append(r.Candidates, candidates[idx].translate(idx, jsonMode))
+	}
+	r.Request = input
+	return r, nil
+}
+
+// streamCandidate accumulates the deltas OpenAI sends for a single choice
+// index over the lifetime of a stream, so they can be joined into a single
+// ai.Candidate once the stream closes.
+type streamCandidate struct {
+	content       strings.Builder
+	toolCalls     map[int]*goopenai.ToolCall
+	toolCallOrder []int
+	finishReason  goopenai.FinishReason
+}
+
+func (acc *streamCandidate) addDelta(choice goopenai.ChatCompletionStreamChoice) {
+	if choice.FinishReason != "" {
+		acc.finishReason = choice.FinishReason
+	}
+	acc.content.WriteString(choice.Delta.Content)
+	for _, tc := range choice.Delta.ToolCalls {
+		idx := 0
+		if tc.Index != nil {
+			idx = *tc.Index
+		}
+		existing, ok := acc.toolCalls[idx]
+		if !ok {
+			tcCopy := tc
+			acc.toolCalls[idx] = &tcCopy
+			acc.toolCallOrder = append(acc.toolCallOrder, idx)
+			continue
+		}
+		existing.Function.Arguments += tc.Function.Arguments
+		if tc.Function.Name != "" {
+			existing.Function.Name = tc.Function.Name
+		}
+		if tc.ID != "" {
+			existing.ID = tc.ID
+		}
+	}
+}
+
+func (acc *streamCandidate) translate(index int, jsonMode bool) *ai.Candidate {
+	c := &ai.Candidate{Index: index}
+	switch acc.finishReason {
+	case goopenai.FinishReasonStop, goopenai.FinishReasonToolCalls:
+		c.FinishReason = ai.FinishReasonStop
+	case goopenai.FinishReasonLength:
+		c.FinishReason = ai.FinishReasonLength
+	case goopenai.FinishReasonContentFilter:
+		c.FinishReason = ai.FinishReasonBlocked
+	case goopenai.FinishReasonFunctionCall:
+		c.FinishReason = ai.FinishReasonOther
+	case goopenai.FinishReasonNull, "":
+		c.FinishReason = ai.FinishReasonUnknown
+	default:
+		c.FinishReason = ai.FinishReasonUnknown
+	}
+
+	m := &ai.Message{Role: ai.RoleModel}
+	if len(acc.toolCalls) > 0 {
+		for _, idx := range acc.toolCallOrder {
+			tc := acc.toolCalls[idx]
+			m.Content = append(m.Content, ai.NewToolRequestPart(&ai.ToolRequest{
+				Ref:   tc.ID,
+				Name:  tc.Function.Name,
+				Input: jsonStringToMap(tc.Function.Arguments),
+			}))
+		}
+		c.Message = m
+		return c
+	}
+
+	if jsonMode {
+		m.Content = append(m.Content, ai.NewDataPart(acc.content.String()))
+	} else {
+		m.Content = append(m.Content, ai.NewTextPart(acc.content.String()))
+	}
+	c.Message = m
+	return c
+}
+
+func convertRequest(model string, input *ai.GenerateRequest) (goopenai.ChatCompletionRequest, error) {
+	messages, err := convertMessages(input.Messages)
+	if err != nil {
+		return goopenai.ChatCompletionRequest{}, err
+	}
+
+	tools, err := convertTools(input.Tools)
+	if err != nil {
+		return goopenai.ChatCompletionRequest{}, err
+	}
+
+	chatCompletionRequest := goopenai.ChatCompletionRequest{
+		Model:    model,
+		Messages: messages,
+		Tools:    tools,
+		N:        input.Candidates,
+	}
+
+	if c := commonConfigFor(input.Config); c != nil {
+		if c.MaxOutputTokens != 0 {
+			chatCompletionRequest.MaxTokens = c.MaxOutputTokens
+		}
+		if len(c.StopSequences) > 0 {
+			chatCompletionRequest.Stop = c.StopSequences
+		}
+		if c.Temperature != 0 {
+			chatCompletionRequest.Temperature = float32(c.Temperature)
+		}
+		if c.TopP != 0 {
+			chatCompletionRequest.TopP = float32(c.TopP)
+		}
+	}
+	applyToolChoiceAndParallelism(&chatCompletionRequest, input.Config)
+
+	if input.Output != nil &&
+		input.Output.Format != "" &&
+		slices.Contains(modelsSupportingResponseFormats, model) {
+		switch input.Output.Format {
+		case ai.OutputFormatJSON:
+			chatCompletionRequest.ResponseFormat = &goopenai.ChatCompletionResponseFormat{
+				Type: goopenai.ChatCompletionResponseFormatTypeJSONObject,
+				JSONSchema: &goopenai.ChatCompletionResponseFormatJSONSchema{
+					Schema: &MapJSONMarshaller{Data: input.Output.Schema},
+					Strict: true,
+				},
+			}
+		case ai.OutputFormatText:
+			chatCompletionRequest.ResponseFormat = &goopenai.ChatCompletionResponseFormat{
+				Type: goopenai.ChatCompletionResponseFormatTypeText,
+			}
+		default:
+			return goopenai.ChatCompletionRequest{}, fmt.Errorf("unknown part type in a request")
+		}
+	}
+
+	return chatCompletionRequest, nil
+}
+
+// MapJSONMarshaller marshals Data as-is, so a map[string]any built from an
+// ai.GenerateRequest's output schema can be passed as an
+// encoding/json.Marshaler wherever the go-openai client expects one.
+type MapJSONMarshaller struct {
+	Data map[string]any
+}
+
+func (m *MapJSONMarshaller) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Data)
+}
+
+func convertMessages(messages []*ai.Message) ([]goopenai.ChatCompletionMessage, error) {
+	var msgs []goopenai.ChatCompletionMessage
+	for _, m := range messages {
+		role := fromAIRoleToOpenAIRole(m.Role)
+		switch role {
+		case goopenai.ChatMessageRoleUser:
+			var multiContent []goopenai.ChatMessagePart
+			for _, part := range m.Content {
+				p, err := toOpenAiTextAndMedia(part)
+				if err != nil {
+					return nil, err
+				}
+				multiContent = append(multiContent, p)
+			}
+			msgs = append(msgs, goopenai.ChatCompletionMessage{
+				Role:         role,
+				MultiContent: multiContent,
+			})
+		case goopenai.ChatMessageRoleSystem:
+			msgs = append(msgs, goopenai.ChatCompletionMessage{
+				Role:    role,
+				Content: m.Content[0].Text,
+			})
+		case goopenai.ChatMessageRoleAssistant:
+			var toolCalls []goopenai.ToolCall
+			for _, part := range m.Content {
+				if !part.IsToolRequest() {
+					continue
+				}
+				toolCalls = append(toolCalls, goopenai.ToolCall{
+					ID:   part.ToolRequest.Ref,
+					Type: goopenai.ToolTypeFunction,
+					Function: goopenai.FunctionCall{
+						Name:      part.ToolRequest.Name,
+						Arguments: mapToJSONString(part.ToolRequest.Input),
+					},
+				})
+			}
+			if len(toolCalls) > 0 {
+				msgs = append(msgs, goopenai.ChatCompletionMessage{
+					Role:      role,
+					ToolCalls: toolCalls,
+				})
+			} else {
+				msgs = append(msgs, goopenai.ChatCompletionMessage{
+					Role:    role,
+					Content: m.Content[0].Text,
+				})
+			}
+		case goopenai.ChatMessageRoleTool:
+			for _, part := range m.Content {
+				msgs = append(msgs, goopenai.ChatCompletionMessage{
+					Role:       role,
+					ToolCallID: part.ToolResponse.Ref,
+					Content:    mapToJSONString(part.ToolResponse.Output),
+					Name:       part.ToolResponse.Name,
+				})
+			}
+		default:
+			return nil, fmt.Errorf("Unknown OpenAI Role %s", role)
+		}
+	}
+	return msgs, nil
+}
+
+func toOpenAiTextAndMedia(part *ai.Part) (goopenai.ChatMessagePart, error) {
+	switch {
+	case part.IsText():
+		return goopenai.ChatMessagePart{
+			Type: goopenai.ChatMessagePartTypeText,
+			Text: part.Text,
+		}, nil
+	case part.IsMedia():
+		return goopenai.ChatMessagePart{
+			Type: goopenai.ChatMessagePartTypeImageURL,
+			ImageURL: &goopenai.ChatMessageImageURL{
+				URL:    part.Text,
+				Detail: goopenai.ImageURLDetailAuto,
+			},
+		}, nil
+	default:
+		return goopenai.ChatMessagePart{}, fmt.Errorf("unknown part type in a request")
+	}
+}
+
+func convertTools(inTools []*ai.ToolDefinition) ([]goopenai.Tool, error) {
+	var outTools []goopenai.Tool
+	for _, t := range inTools {
+		fd, err := buildFunctionDefinition(t)
+		if err != nil {
+			return nil, err
+		}
+		outTools = append(outTools, goopenai.Tool{
+			Type:     goopenai.ToolTypeFunction,
+			Function: fd,
+		})
+	}
+	return outTools, nil
+}
+
+// translateResponse translates a goopenai.ChatCompletionResponse into an
+// ai.GenerateResponse.
+func translateResponse(resp goopenai.ChatCompletionResponse, jsonMode bool) *ai.GenerateResponse {
+	r := &ai.GenerateResponse{}
+
+	for _, c := range resp.Choices {
+		r.Candidates = append(r.Candidates, translateCandidate(c, jsonMode))
+	}
+
+	r.Usage = &ai.GenerationUsage{
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+		TotalTokens:  resp.Usage.TotalTokens,
+	}
+	r.Custom = resp
+	return r
+}
+
+func fromAIRoleToOpenAIRole(aiRole ai.Role) string {
+	switch aiRole {
+	case ai.RoleUser:
+		return goopenai.ChatMessageRoleUser
+	case ai.RoleSystem:
+		return goopenai.ChatMessageRoleSystem
+	case ai.RoleModel:
+		return goopenai.ChatMessageRoleAssistant
+	case ai.RoleTool:
+		return goopenai.ChatMessageRoleTool
+	default:
+		panic(fmt.Sprintf("Unknown ai.Role: %s", aiRole))
+	}
+}
+
+// translateCandidate translates from a goopenai.ChatCompletionChoice to an ai.Candidate.
+func translateCandidate(choice goopenai.ChatCompletionChoice, jsonMode bool) *ai.Candidate {
+	c := &ai.Candidate{
+		Index: choice.Index,
+	}
+	switch choice.FinishReason {
+	case goopenai.FinishReasonStop, goopenai.FinishReasonToolCalls:
+		c.FinishReason = ai.FinishReasonStop
+	case goopenai.FinishReasonLength:
+		c.FinishReason = ai.FinishReasonLength
+	case goopenai.FinishReasonContentFilter:
+		c.FinishReason = ai.FinishReasonBlocked
+	case goopenai.FinishReasonFunctionCall:
+		c.FinishReason = ai.FinishReasonOther
+	case goopenai.FinishReasonNull:
+		c.FinishReason = ai.FinishReasonUnknown
+	default:
+		c.FinishReason = ai.FinishReasonUnknown
+	}
+	m := &ai.Message{
+		Role: ai.RoleModel,
+	}
+
+	// handle tool calls
+	var toolRequestParts []*ai.Part
+	for _, toolCall := range choice.Message.ToolCalls {
+		toolRequestParts = append(toolRequestParts, ai.NewToolRequestPart(&ai.ToolRequest{
+			Ref:   toolCall.ID,
+			Name:  toolCall.Function.Name,
+			Input: jsonStringToMap(toolCall.Function.Arguments),
+		}))
+	}
+	if len(toolRequestParts) > 0 {
+		m.Content = toolRequestParts
+		c.Message = m
+		return c
+	}
+
+	if jsonMode {
+		m.Content = append(m.Content, ai.NewDataPart(choice.Message.Content))
+	} else {
+		m.Content = append(m.Content, ai.NewTextPart(choice.Message.Content))
+	}
+
+	c.Message = m
+	return c
+}
+
+func jsonStringToMap(jsonString string) map[string]any {
+	var result map[string]any
+	if err := json.Unmarshal([]byte(jsonString), &result); err != nil {
+		panic(fmt.Errorf("unmarshal failed to parse json string %s: %w", jsonString, err))
+	}
+	return result
+}
+
+func mapToJSONString(data map[string]any) string {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal map to JSON string: data, %#v %w", data, err))
+	}
+	return string(jsonBytes)
+}
+
+func mapToJSONRawMessage(data map[string]any) (json.RawMessage, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal map to JSON string: data, %#v %w", data, err)
+	}
+	return json.RawMessage(jsonBytes), nil
+}
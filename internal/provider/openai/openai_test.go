@@ -0,0 +1,70 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/firebase/genkit/go/ai"
+	goopenai "github.com/sashabaranov/go-openai"
+)
+
+// TestToolCallIDRoundTrip verifies that when the model returns multiple tool
+// calls in one turn, each becomes a distinct ai.NewToolRequestPart carrying
+// the OpenAI ToolCall.ID (not the function name), and that ID comes back out
+// as ToolCallID when the corresponding RoleTool response is converted back
+// into a request.
+func TestToolCallIDRoundTrip(t *testing.T) {
+	choice := goopenai.ChatCompletionChoice{
+		FinishReason: goopenai.FinishReasonToolCalls,
+		Message: goopenai.ChatCompletionMessage{
+			ToolCalls: []goopenai.ToolCall{
+				{
+					ID:       "call_1",
+					Type:     goopenai.ToolTypeFunction,
+					Function: goopenai.FunctionCall{Name: "getWeather", Arguments: `{"city":"Tokyo"}`},
+				},
+				{
+					ID:       "call_2",
+					Type:     goopenai.ToolTypeFunction,
+					Function: goopenai.FunctionCall{Name: "getWeather", Arguments: `{"city":"Osaka"}`},
+				},
+			},
+		},
+	}
+
+	candidate := translateCandidate(choice, false)
+	if len(candidate.Message.Content) != 2 {
+		t.Fatalf("got %d tool request parts, want 2", len(candidate.Message.Content))
+	}
+	for i, wantID := range []string{"call_1", "call_2"} {
+		part := candidate.Message.Content[i]
+		if !part.IsToolRequest() {
+			t.Fatalf("part %d: got %#v, want a tool request", i, part)
+		}
+		if part.ToolRequest.Ref != wantID {
+			t.Errorf("part %d: got Ref %q, want %q", i, part.ToolRequest.Ref, wantID)
+		}
+		if part.ToolRequest.Name != "getWeather" {
+			t.Errorf("part %d: got Name %q, want %q", i, part.ToolRequest.Name, "getWeather")
+		}
+	}
+
+	// Round-trip: the tool responses must carry ToolCallID back to the
+	// exact call they answer, not the function name.
+	toolMessage := &ai.Message{
+		Role: ai.RoleTool,
+		Content: []*ai.Part{
+			ai.NewToolResponsePart(&ai.ToolResponse{
+				Ref:    candidate.Message.Content[0].ToolRequest.Ref,
+				Name:   "getWeather",
+				Output: map[string]any{"tempC": 18},
+			}),
+		},
+	}
+	msgs, err := convertMessages([]*ai.Message{toolMessage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := msgs[0].ToolCallID, "call_1"; got != want {
+		t.Errorf("got ToolCallID %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package openai
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/firebase/genkit/go/ai"
+	goopenai "github.com/sashabaranov/go-openai"
+)
+
+// toolConfigMetadataKey is the ai.ToolDefinition.Metadata key under which a
+// *ToolConfig is attached to opt a single tool into OpenAI-specific
+// tool-calling behavior.
+const toolConfigMetadataKey = "openai"
+
+// ToolConfig carries OpenAI-specific tool-calling options for a single tool.
+// Attach one under the [toolConfigMetadataKey] ("openai") key of an
+// ai.ToolDefinition's Metadata.
+type ToolConfig struct {
+	// Strict requests OpenAI's constrained-decoding guarantee for this
+	// tool's arguments. InputSchema is automatically tightened to satisfy
+	// strict mode's requirements (every property required, no top-level
+	// oneOf/anyOf, additionalProperties: false).
+	Strict bool
+}
+
+// RequestConfig extends ai.GenerationCommonConfig with chat-completion
+// options this plugin does not otherwise expose. Pass *RequestConfig as an
+// ai.GenerateRequest's Config to set ToolChoice or ParallelToolCalls
+// alongside the common fields.
+type RequestConfig struct {
+	ai.GenerationCommonConfig
+	// ToolChoice controls how the model selects tools. Valid values are the
+	// strings "none", "auto", "required", or a value built with
+	// [ToolChoiceFunction] to force a specific function.
+	ToolChoice any
+	// ParallelToolCalls controls whether the model may return more than one
+	// tool call in a single turn. Nil leaves the API default (true) in
+	// place.
+	ParallelToolCalls *bool
+}
+
+// ToolChoiceFunction returns a ToolChoice value that forces the model to
+// call the named function.
+func ToolChoiceFunction(name string) goopenai.ToolChoice {
+	return goopenai.ToolChoice{
+		Type:     goopenai.ToolTypeFunction,
+		Function: goopenai.ToolFunction{Name: name},
+	}
+}
+
+// applyToolChoiceAndParallelism reads the OpenAI-specific options out of
+// input.Config, if present, and applies them to req.
+func applyToolChoiceAndParallelism(req *goopenai.ChatCompletionRequest, config any) {
+	rc, ok := config.(*RequestConfig)
+	if !ok || rc == nil {
+		return
+	}
+	if rc.ToolChoice != nil {
+		req.ToolChoice = rc.ToolChoice
+	}
+	if rc.ParallelToolCalls != nil {
+		req.ParallelToolCalls = *rc.ParallelToolCalls
+	}
+}
+
+// commonConfigFor returns the ai.GenerationCommonConfig embedded in an
+// ai.GenerateRequest's Config, whether it was passed directly or via
+// *RequestConfig.
+func commonConfigFor(config any) *ai.GenerationCommonConfig {
+	switch c := config.(type) {
+	case *ai.GenerationCommonConfig:
+		return c
+	case *RequestConfig:
+		if c == nil {
+			return nil
+		}
+		return &c.GenerationCommonConfig
+	default:
+		return nil
+	}
+}
+
+// buildFunctionDefinition converts a tool into a goopenai.FunctionDefinition,
+// honoring a Strict [ToolConfig] attached to the tool's metadata.
+func buildFunctionDefinition(t *ai.ToolDefinition) (*goopenai.FunctionDefinition, error) {
+	schema := t.InputSchema
+	strict := toolConfigFor(t) != nil && toolConfigFor(t).Strict
+	if strict {
+		var err error
+		schema, err = tightenSchemaForStrictMode(schema)
+		if err != nil {
+			return nil, fmt.Errorf("openai: tool %q: %w", t.Name, err)
+		}
+	}
+
+	parameters, err := mapToJSONRawMessage(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &goopenai.FunctionDefinition{
+		Name:        t.Name,
+		Description: t.Description,
+		Parameters:  parameters,
+		Strict:      strict,
+	}, nil
+}
+
+func toolConfigFor(t *ai.ToolDefinition) *ToolConfig {
+	if t.Metadata == nil {
+		return nil
+	}
+	tc, _ := t.Metadata[toolConfigMetadataKey].(*ToolConfig)
+	return tc
+}
+
+// tightenSchemaForStrictMode validates and rewrites schema so it satisfies
+// OpenAI strict mode's subset of JSON Schema: no top-level oneOf/anyOf, every
+// property required, and additionalProperties: false. Only the top-level
+// object is rewritten; nested object schemas are passed through unchanged.
+func tightenSchemaForStrictMode(schema map[string]any) (map[string]any, error) {
+	if _, ok := schema["oneOf"]; ok {
+		return nil, fmt.Errorf("strict mode does not support a top-level oneOf")
+	}
+	if _, ok := schema["anyOf"]; ok {
+		return nil, fmt.Errorf("strict mode does not support a top-level anyOf")
+	}
+
+	tightened := make(map[string]any, len(schema))
+	for k, v := range schema {
+		tightened[k] = v
+	}
+	tightened["additionalProperties"] = false
+
+	properties, _ := tightened["properties"].(map[string]any)
+	if len(properties) > 0 {
+		required := make([]string, 0, len(properties))
+		for name := range properties {
+			required = append(required, name)
+		}
+		sort.Strings(required)
+		tightened["required"] = required
+	}
+
+	return tightened, nil
+}
@@ -0,0 +1,54 @@
+// Package provider defines a vendor-neutral interface for chat-completion
+// backends so that a single Genkit plugin surface can host models from
+// OpenAI, Anthropic, and Google Gemini side by side. Each vendor gets its
+// own adapter under internal/provider/<vendor> that implements Client by
+// translating to and from that vendor's wire format; the plugins in
+// plugins/<vendor> wire an adapter up to [ai.DefineModel].
+package provider
+
+import (
+	"context"
+
+	"github.com/firebase/genkit/go/ai"
+)
+
+// Client is implemented by each vendor adapter.
+type Client interface {
+	// Generate issues a single, non-streaming chat completion.
+	Generate(ctx context.Context, model string, req *ai.GenerateRequest) (*ai.GenerateResponse, error)
+	// Stream issues a streaming chat completion, invoking cb for each chunk
+	// as it arrives and returning the final response synthesized once the
+	// stream closes.
+	Stream(ctx context.Context, model string, req *ai.GenerateRequest, cb func(context.Context, *ai.GenerateResponseChunk) error) (*ai.GenerateResponse, error)
+}
+
+// FinishReasonMap translates a vendor's native finish/stop-reason strings
+// (e.g. Anthropic's "end_turn" or Gemini's "STOP") into the Genkit
+// equivalent. Reasons with no entry map to ai.FinishReasonUnknown.
+type FinishReasonMap map[string]ai.FinishReason
+
+// Lookup returns the mapped ai.FinishReason for reason, or
+// ai.FinishReasonUnknown if reason has no entry.
+func (m FinishReasonMap) Lookup(reason string) ai.FinishReason {
+	if fr, ok := m[reason]; ok {
+		return fr
+	}
+	return ai.FinishReasonUnknown
+}
+
+// Usage is the vendor-neutral token accounting each adapter fills in from
+// its own response shape.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+	TotalTokens  int
+}
+
+// ToAI converts u to the equivalent [ai.GenerationUsage].
+func (u Usage) ToAI() *ai.GenerationUsage {
+	return &ai.GenerationUsage{
+		InputTokens:  u.InputTokens,
+		OutputTokens: u.OutputTokens,
+		TotalTokens:  u.TotalTokens,
+	}
+}
@@ -61,6 +61,36 @@ func main() {
 		return text, nil
 	})
 
+	// Define a flow that generates an image of whatever the caller describes,
+	// using DALL·E.
+	genkit.DefineFlow("imageOfFlow", func(ctx context.Context, subject string) (string, error) {
+		resp, err := openai.GenerateImage(ctx, &openai.ImageRequest{
+			Model:  "dall-e-3",
+			Prompt: fmt.Sprintf("An image of %s", subject),
+			Size:   "1024x1024",
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(resp.Media) == 0 {
+			return "", fmt.Errorf("imageOfFlow: no image returned for %q", subject)
+		}
+		return resp.Media[0].Text, nil
+	})
+
+	// Define a flow that transcribes an audio file at the given path using
+	// Whisper.
+	genkit.DefineFlow("transcribeFlow", func(ctx context.Context, audioPath string) (string, error) {
+		resp, err := openai.Transcribe(ctx, &openai.TranscriptionRequest{
+			Model:    "whisper-1",
+			FilePath: audioPath,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Text, nil
+	})
+
 	// Initialize Genkit and start a flow server. This call must come last,
 	// after all of your plug-in configuration and flow definitions. When you
 	// pass a nil configuration to Init, Genkit starts a local flow server,
@@ -0,0 +1,126 @@
+// Package anthropic is a Genkit plugin for using Anthropic's Claude models,
+// built on the shared [provider.Client] abstraction.
+package anthropic
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/yukinagae/genkit-golang-openai-sample/internal/provider"
+	internalanthropic "github.com/yukinagae/genkit-golang-openai-sample/internal/provider/anthropic"
+)
+
+const (
+	providerID  = "anthropic"
+	labelPrefix = "Anthropic"
+	apiKeyEnv   = "ANTHROPIC_API_KEY"
+)
+
+var state struct {
+	mu      sync.Mutex
+	initted bool
+	client  provider.Client
+}
+
+var knownCaps = map[string]ai.ModelCapabilities{
+	"claude-3-5-sonnet-latest": {Multiturn: true, Tools: true, SystemRole: true, Media: true},
+	"claude-3-5-haiku-latest":  {Multiturn: true, Tools: true, SystemRole: true, Media: true},
+	"claude-3-opus-latest":     {Multiturn: true, Tools: true, SystemRole: true, Media: true},
+}
+
+// Config is the configuration for the plugin.
+type Config struct {
+	// The API key to access the service.
+	// If empty, the value of the environment variable ANTHROPIC_API_KEY will be consulted.
+	APIKey string
+	// BaseURL overrides the default Anthropic API endpoint.
+	BaseURL string
+}
+
+// Init initializes the plugin and all known models.
+// After calling Init, you may call [DefineModel] to create and register any additional generative models.
+func Init(ctx context.Context, cfg *Config) (err error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.initted {
+		panic(providerID + ".Init already called")
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("%s.Init: %w", providerID, err)
+		}
+	}()
+
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv(apiKeyEnv)
+		if apiKey == "" {
+			return fmt.Errorf("Anthropic requires setting %s in the environment. You can get an API key at https://console.anthropic.com/settings/keys", apiKeyEnv)
+		}
+	}
+
+	state.client = internalanthropic.New(apiKey, cfg.BaseURL)
+	state.initted = true
+	for model, caps := range knownCaps {
+		defineModel(model, caps)
+	}
+	return nil
+}
+
+// DefineModel defines an unknown model with the given name.
+// The second argument describes the capability of the model.
+// Use [IsDefinedModel] to determine if a model is already defined.
+// After [Init] is called, only the known models are defined.
+func DefineModel(name string, caps *ai.ModelCapabilities) (ai.Model, error) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if !state.initted {
+		panic(providerID + ".Init not called")
+	}
+	var mc ai.ModelCapabilities
+	if caps == nil {
+		var ok bool
+		mc, ok = knownCaps[name]
+		if !ok {
+			return nil, fmt.Errorf("%s.DefineModel: called with unknown model %q and nil ModelCapabilities", providerID, name)
+		}
+	} else {
+		mc = *caps
+	}
+	return defineModel(name, mc), nil
+}
+
+// requires state.mu
+func defineModel(name string, caps ai.ModelCapabilities) ai.Model {
+	meta := &ai.ModelMetadata{
+		Label:    labelPrefix + " - " + name,
+		Supports: caps,
+	}
+	return ai.DefineModel(providerID, name, meta, func(
+		ctx context.Context,
+		input *ai.GenerateRequest,
+		cb func(context.Context, *ai.GenerateResponseChunk) error,
+	) (*ai.GenerateResponse, error) {
+		if cb != nil {
+			return state.client.Stream(ctx, name, input, cb)
+		}
+		return state.client.Generate(ctx, name, input)
+	})
+}
+
+// IsDefinedModel reports whether the named [Model] is defined by this plugin.
+func IsDefinedModel(name string) bool {
+	return ai.IsDefinedModel(providerID, name)
+}
+
+// Model returns the [ai.Model] with the given name.
+// It returns nil if the model was not defined.
+func Model(name string) ai.Model {
+	return ai.LookupModel(providerID, name)
+}
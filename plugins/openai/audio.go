@@ -0,0 +1,94 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit"
+	goopenai "github.com/sashabaranov/go-openai"
+)
+
+// TranscriptionRequest is the input to the "transcribe" action registered by
+// this plugin.
+type TranscriptionRequest struct {
+	// Model is the transcription model to use, e.g. "whisper-1".
+	Model string
+	// FilePath is the path to the audio file to transcribe. Exactly one of
+	// FilePath or Bytes must be set.
+	FilePath string
+	// Bytes is the raw audio content to transcribe; FileName should carry
+	// its extension so the API can infer the format. Exactly one of
+	// FilePath or Bytes must be set.
+	Bytes    []byte
+	FileName string
+	// Language is an optional ISO-639-1 language hint.
+	Language string
+	// WordTimestamps requests word-level timestamps in the response.
+	WordTimestamps bool
+}
+
+// TranscriptionResponse is the output of the "transcribe" action.
+type TranscriptionResponse struct {
+	Text  string
+	Words []WordTimestamp
+}
+
+// WordTimestamp is a single transcribed word and its position in the audio.
+type WordTimestamp struct {
+	Word  string
+	Start float64
+	End   float64
+}
+
+var transcriptionAction *core.Action[*TranscriptionRequest, *TranscriptionResponse]
+
+// requires state.mu
+func defineTranscriptionAction() {
+	transcriptionAction = genkit.DefineAction(provider, "transcribe", core.ActionTypeCustom, nil,
+		func(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResponse, error) {
+			return transcribe(ctx, state.rawClient, req)
+		})
+}
+
+// Transcribe transcribes req using Whisper. It can be called directly, or
+// the same capability can be driven through the developer UI via the
+// registered "transcribe" action.
+func Transcribe(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResponse, error) {
+	return transcriptionAction.Run(ctx, req, nil)
+}
+
+func transcribe(ctx context.Context, client *goopenai.Client, req *TranscriptionRequest) (*TranscriptionResponse, error) {
+	apiReq := goopenai.AudioRequest{
+		Model:    req.Model,
+		Language: req.Language,
+	}
+	if req.WordTimestamps {
+		apiReq.Format = goopenai.AudioResponseFormatVerboseJSON
+		apiReq.TimestampGranularities = []goopenai.TranscriptionTimestampGranularity{
+			goopenai.TranscriptionTimestampGranularityWord,
+		}
+	}
+
+	switch {
+	case req.FilePath != "":
+		apiReq.FilePath = req.FilePath
+	case len(req.Bytes) > 0:
+		apiReq.FilePath = req.FileName
+		apiReq.Reader = bytes.NewReader(req.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: transcription request must set FilePath or Bytes", provider)
+	}
+
+	resp, err := client.CreateTranscription(ctx, apiReq)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TranscriptionResponse{Text: resp.Text}
+	for _, w := range resp.Words {
+		out.Words = append(out.Words, WordTimestamp{Word: w.Word, Start: w.Start, End: w.End})
+	}
+	return out, nil
+}
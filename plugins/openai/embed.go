@@ -0,0 +1,126 @@
+package openai
+
+import (
+	"context"
+
+	"github.com/firebase/genkit/go/ai"
+	goopenai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	// embeddingMaxInputs is the largest number of inputs the embeddings
+	// endpoint accepts in a single request.
+	embeddingMaxInputs = 2048
+	// embeddingMaxTokens is the largest number of tokens the embeddings
+	// endpoint accepts across all inputs in a single request.
+	embeddingMaxTokens = 8191
+)
+
+// knownEmbedders maps the embedder names this plugin registers by default to
+// their go-openai model constant.
+var knownEmbedders = map[string]goopenai.EmbeddingModel{
+	"text-embedding-3-small": goopenai.SmallEmbedding3,
+	"text-embedding-3-large": goopenai.LargeEmbedding3,
+	"text-embedding-ada-002": goopenai.AdaEmbeddingV2,
+}
+
+// dimensionsCapableModels are the embedding models that accept the
+// Dimensions request parameter. text-embedding-ada-002 predates it and
+// rejects the field.
+var dimensionsCapableModels = map[goopenai.EmbeddingModel]bool{
+	goopenai.SmallEmbedding3: true,
+	goopenai.LargeEmbedding3: true,
+}
+
+// requires state.mu
+func defineEmbedder(name string, model goopenai.EmbeddingModel, dimensions int) ai.Embedder {
+	return ai.DefineEmbedder(provider, name, func(ctx context.Context, input *ai.EmbedRequest) (*ai.EmbedResponse, error) {
+		return embed(ctx, state.rawClient, model, dimensions, input)
+	})
+}
+
+// IsDefinedEmbedder reports whether the named [Embedder] is defined by this plugin.
+func IsDefinedEmbedder(name string) bool {
+	return ai.IsDefinedEmbedder(provider, name)
+}
+
+// Embedder returns the [ai.Embedder] with the given name.
+// It returns nil if the embedder was not defined.
+func Embedder(name string) ai.Embedder {
+	return ai.LookupEmbedder(provider, name)
+}
+
+func embed(
+	ctx context.Context,
+	client *goopenai.Client,
+	model goopenai.EmbeddingModel,
+	dimensions int,
+	input *ai.EmbedRequest,
+) (*ai.EmbedResponse, error) {
+	texts := make([]string, len(input.Documents))
+	for i, doc := range input.Documents {
+		texts[i] = doc.Text()
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, batch := range chunkEmbeddingInputs(texts, embeddingMaxInputs, embeddingMaxTokens) {
+		req := goopenai.EmbeddingRequest{
+			Input: goopenai.EmbeddingRequestStrings(batch.texts),
+			Model: model,
+		}
+		if dimensions > 0 && dimensionsCapableModels[model] {
+			req.Dimensions = dimensions
+		}
+		resp, err := client.CreateEmbeddings(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Data {
+			embeddings[batch.offset+e.Index] = e.Embedding
+		}
+	}
+
+	r := &ai.EmbedResponse{}
+	for _, e := range embeddings {
+		r.Embeddings = append(r.Embeddings, &ai.DocumentEmbedding{Embedding: e})
+	}
+	return r, nil
+}
+
+// embeddingBatch is one chunk of texts sized to respect the embeddings
+// endpoint's per-request input and token limits, along with its offset into
+// the original slice so results can be placed back in order.
+type embeddingBatch struct {
+	texts  []string
+	offset int
+}
+
+// chunkEmbeddingInputs splits texts into batches that respect both the
+// embeddings endpoint's per-request input count limit and its per-request
+// token limit, starting a new batch whenever adding the next input would
+// exceed either one.
+func chunkEmbeddingInputs(texts []string, maxInputs, maxTokens int) []embeddingBatch {
+	var batches []embeddingBatch
+	start := 0
+	tokens := 0
+	for i, text := range texts {
+		t := estimateTokens(text)
+		if i > start && (i-start >= maxInputs || tokens+t > maxTokens) {
+			batches = append(batches, embeddingBatch{texts: texts[start:i], offset: start})
+			start = i
+			tokens = 0
+		}
+		tokens += t
+	}
+	if start < len(texts) {
+		batches = append(batches, embeddingBatch{texts: texts[start:], offset: start})
+	}
+	return batches
+}
+
+// estimateTokens approximates the number of tokens text will consume, absent
+// a vendored tokenizer. OpenAI's rule of thumb is roughly 4 characters per
+// token for English text.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
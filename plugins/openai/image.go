@@ -0,0 +1,85 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/firebase/genkit/go/ai"
+	"github.com/firebase/genkit/go/core"
+	"github.com/firebase/genkit/go/genkit"
+	goopenai "github.com/sashabaranov/go-openai"
+)
+
+// ImageRequest is the input to the "image" action registered by this plugin.
+type ImageRequest struct {
+	// Model is the image model to use, e.g. "dall-e-3" or "gpt-image-1".
+	Model string
+	// Prompt describes the image to generate.
+	Prompt string
+	// Size is the requested pixel dimensions, e.g. "1024x1024".
+	Size string
+	// Quality is "standard" or "hd" (dall-e-3 only).
+	Quality string
+	// N is the number of images to generate. Defaults to 1.
+	N int
+	// ResponseFormat is "b64_json" or "url". Defaults to "b64_json".
+	ResponseFormat goopenai.ImageResponseFormat
+}
+
+// ImageResponse is the output of the "image" action.
+type ImageResponse struct {
+	Media []*ai.Part
+}
+
+var imageAction *core.Action[*ImageRequest, *ImageResponse]
+
+// requires state.mu
+func defineImageAction() {
+	imageAction = genkit.DefineAction(provider, "image", core.ActionTypeCustom, nil,
+		func(ctx context.Context, req *ImageRequest) (*ImageResponse, error) {
+			return generateImage(ctx, state.rawClient, req)
+		})
+}
+
+// GenerateImage generates one or more images from req using DALL·E or
+// gpt-image-1. It can be called directly, or the same capability can be
+// driven through the developer UI via the registered "image" action.
+func GenerateImage(ctx context.Context, req *ImageRequest) (*ImageResponse, error) {
+	return imageAction.Run(ctx, req, nil)
+}
+
+func generateImage(ctx context.Context, client *goopenai.Client, req *ImageRequest) (*ImageResponse, error) {
+	n := req.N
+	if n == 0 {
+		n = 1
+	}
+	responseFormat := req.ResponseFormat
+	if responseFormat == "" {
+		responseFormat = goopenai.CreateImageResponseFormatB64JSON
+	}
+
+	resp, err := client.CreateImage(ctx, goopenai.ImageRequest{
+		Model:          req.Model,
+		Prompt:         req.Prompt,
+		Size:           req.Size,
+		Quality:        req.Quality,
+		N:              n,
+		ResponseFormat: responseFormat,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ImageResponse{}
+	for _, d := range resp.Data {
+		switch {
+		case d.B64JSON != "":
+			out.Media = append(out.Media, ai.NewMediaPart("image/png", "data:image/png;base64,"+d.B64JSON))
+		case d.URL != "":
+			out.Media = append(out.Media, ai.NewMediaPart("", d.URL))
+		default:
+			return nil, fmt.Errorf("%s: image response contained neither a URL nor base64 data", provider)
+		}
+	}
+	return out, nil
+}
@@ -1,14 +1,18 @@
+// Package openai is a Genkit plugin for using OpenAI's chat models, built on
+// the shared [provider.Client] abstraction. Embeddings, image generation,
+// and transcription don't fit that generic chat interface, so this package
+// still drives the raw go-openai client directly for those.
 package openai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
-	"slices"
 	"sync"
 
 	"github.com/firebase/genkit/go/ai"
+	providerpkg "github.com/yukinagae/genkit-golang-openai-sample/internal/provider"
+	internalopenai "github.com/yukinagae/genkit-golang-openai-sample/internal/provider/openai"
 	goopenai "github.com/sashabaranov/go-openai"
 )
 
@@ -21,34 +25,53 @@ const (
 var state struct {
 	mu      sync.Mutex
 	initted bool
-	client  *goopenai.Client
-}
-
-var (
-	knownCaps = map[string]ai.ModelCapabilities{
-		goopenai.GPT4o:     Multimodal,
-		goopenai.GPT4oMini: Multimodal,
-		goopenai.GPT4Turbo: Multimodal,
-		goopenai.GPT4:      BasicText,
-	}
-
-	modelsSupportingResponseFormats = []string{
-		goopenai.GPT4o,     //
-		goopenai.GPT4oMini, //
-		goopenai.GPT4Turbo, //
-	}
+	// rawClient talks directly to go-openai for the capabilities that don't
+	// fit the shared provider.Client interface: embeddings, images, audio.
+	rawClient *goopenai.Client
+	// chatClient is the shared adapter models are defined against.
+	chatClient providerpkg.Client
+}
+
+var knownCaps = map[string]ai.ModelCapabilities{
+	goopenai.GPT4o:     Multimodal,
+	goopenai.GPT4oMini: Multimodal,
+	goopenai.GPT4Turbo: Multimodal,
+	goopenai.GPT4:      BasicText,
+}
+
+// ToolConfig, RequestConfig, and ToolChoiceFunction are re-exported from the
+// internal adapter so callers can configure strict tool schemas and
+// tool-choice/parallelism without importing an internal package.
+type (
+	ToolConfig    = internalopenai.ToolConfig
+	RequestConfig = internalopenai.RequestConfig
 )
 
+// ToolChoiceFunction returns a ToolChoice value that forces the model to
+// call the named function. See [RequestConfig.ToolChoice].
+func ToolChoiceFunction(name string) any {
+	return internalopenai.ToolChoiceFunction(name)
+}
+
 // Config is the configuration for the plugin.
 type Config struct {
 	// The API key to access the service.
 	// If empty, the values of the environment variables OPENAI_API_KEY will be consulted.
 	APIKey string
-}
-
-// Init initializes the plugin and all known models.
+	// BaseURL overrides the default OpenAI API endpoint. Set this to point the
+	// plugin at any OpenAI-wire-compatible server, such as Ollama, LocalAI,
+	// vLLM, Groq, or OpenRouter.
+	BaseURL string
+	// OrgID sets the OpenAI-Organization header sent with each request.
+	OrgID string
+	// Dimensions requests shortened embedding vectors from the v3 embedding
+	// models (e.g. 256 or 1024). If zero, each model's default dimensionality
+	// is used.
+	Dimensions int
+}
+
+// Init initializes the plugin and all known models and embedders.
 // After calling Init, you may call [DefineModel] to create and register any additional generative models.
-// TODO: initialize embedders
 func Init(ctx context.Context, cfg *Config) (err error) {
 	if cfg == nil {
 		cfg = &Config{}
@@ -67,17 +90,37 @@ func Init(ctx context.Context, cfg *Config) (err error) {
 	apiKey := cfg.APIKey
 	if apiKey == "" {
 		apiKey = os.Getenv(apiKeyEnv)
-		if apiKey == "" {
+		// Some OpenAI-compatible servers reachable via BaseURL (e.g. a local
+		// Ollama instance) don't require an API key at all.
+		if apiKey == "" && cfg.BaseURL == "" {
 			return fmt.Errorf("OpenAI requires setting %s in the environment. You can get an API key at https://platform.openai.com/api-keys", apiKeyEnv)
 		}
 	}
 
-	client := goopenai.NewClient(apiKey)
-	state.client = client
+	var client *goopenai.Client
+	if cfg.BaseURL != "" || cfg.OrgID != "" {
+		clientConfig := goopenai.DefaultConfig(apiKey)
+		if cfg.BaseURL != "" {
+			clientConfig.BaseURL = cfg.BaseURL
+		}
+		if cfg.OrgID != "" {
+			clientConfig.OrgID = cfg.OrgID
+		}
+		client = goopenai.NewClientWithConfig(clientConfig)
+	} else {
+		client = goopenai.NewClient(apiKey)
+	}
+	state.rawClient = client
+	state.chatClient = internalopenai.New(client)
 	state.initted = true
 	for model, caps := range knownCaps {
 		defineModel(model, caps)
 	}
+	for name, model := range knownEmbedders {
+		defineEmbedder(name, model, cfg.Dimensions)
+	}
+	defineImageAction()
+	defineTranscriptionAction()
 	return nil
 }
 
@@ -85,6 +128,9 @@ func Init(ctx context.Context, cfg *Config) (err error) {
 // The second argument describes the capability of the model.
 // Use [IsDefinedModel] to determine if a model is already defined.
 // After [Init] is called, only the known models are defined.
+// Passing a non-nil caps bypasses the knownCaps lookup entirely, so this also
+// registers model names an OpenAI-compatible backend serves that knownCaps
+// has no entry for, such as "llama3.1:8b" or "mixtral-8x7b-32768".
 func DefineModel(name string, caps *ai.ModelCapabilities) (ai.Model, error) {
 	state.mu.Lock()
 	defer state.mu.Unlock()
@@ -115,7 +161,10 @@ func defineModel(name string, caps ai.ModelCapabilities) ai.Model {
 		input *ai.GenerateRequest,
 		cb func(context.Context, *ai.GenerateResponseChunk) error,
 	) (*ai.GenerateResponse, error) {
-		return generate(ctx, state.client, name, input, cb)
+		if cb != nil {
+			return state.chatClient.Stream(ctx, name, input, cb)
+		}
+		return state.chatClient.Generate(ctx, name, input)
 	})
 }
 
@@ -129,316 +178,3 @@ func IsDefinedModel(name string) bool {
 func Model(name string) ai.Model {
 	return ai.LookupModel(provider, name)
 }
-
-func generate(
-	ctx context.Context,
-	client *goopenai.Client,
-	model string,
-	input *ai.GenerateRequest,
-	cb func(context.Context, *ai.GenerateResponseChunk) error, // TODO: implement streaming
-) (*ai.GenerateResponse, error) {
-	req, err := convertRequest(model, input)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := client.CreateChatCompletion(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-
-	jsonMode := false
-	if input.Output != nil &&
-		input.Output.Format == ai.OutputFormatJSON {
-		jsonMode = true
-	}
-	r := translateResponse(resp, jsonMode)
-	r.Request = input
-	return r, nil
-}
-
-func convertRequest(model string, input *ai.GenerateRequest) (goopenai.ChatCompletionRequest, error) {
-	messages, err := convertMessages(input.Messages)
-	if err != nil {
-		return goopenai.ChatCompletionRequest{}, err
-	}
-
-	tools, err := convertTools(input.Tools)
-	if err != nil {
-		return goopenai.ChatCompletionRequest{}, err
-	}
-
-	chatCompletionRequest := goopenai.ChatCompletionRequest{
-		Model:    model,
-		Messages: messages,
-		Tools:    tools,
-		N:        input.Candidates,
-	}
-
-	if c, ok := input.Config.(*ai.GenerationCommonConfig); ok && c != nil {
-		if c.MaxOutputTokens != 0 {
-			chatCompletionRequest.MaxTokens = c.MaxOutputTokens
-		}
-		if len(c.StopSequences) > 0 {
-			chatCompletionRequest.Stop = c.StopSequences
-		}
-		if c.Temperature != 0 {
-			chatCompletionRequest.Temperature = float32(c.Temperature)
-		}
-		if c.TopP != 0 {
-			chatCompletionRequest.TopP = float32(c.TopP)
-		}
-	}
-
-	if input.Output != nil &&
-		input.Output.Format != "" &&
-		slices.Contains(modelsSupportingResponseFormats, model) {
-		switch input.Output.Format {
-		case ai.OutputFormatJSON:
-			chatCompletionRequest.ResponseFormat = &goopenai.ChatCompletionResponseFormat{
-				Type: goopenai.ChatCompletionResponseFormatTypeJSONObject,
-				JSONSchema: &goopenai.ChatCompletionResponseFormatJSONSchema{
-					Schema: &MapJSONMarshaller{Data: input.Output.Schema},
-					Strict: true,
-				},
-			}
-		case ai.OutputFormatText:
-			chatCompletionRequest.ResponseFormat = &goopenai.ChatCompletionResponseFormat{
-				Type: goopenai.ChatCompletionResponseFormatTypeText,
-			}
-		default:
-			return goopenai.ChatCompletionRequest{}, fmt.Errorf("unknown part type in a request")
-		}
-	}
-
-	return chatCompletionRequest, nil
-}
-
-type MapJSONMarshaller struct {
-	Data map[string]any
-}
-
-func (m *MapJSONMarshaller) MarshalJSON() ([]byte, error) {
-	return json.Marshal(m.Data)
-}
-
-func convertMessages(messages []*ai.Message) ([]goopenai.ChatCompletionMessage, error) {
-	var msgs []goopenai.ChatCompletionMessage
-	for _, m := range messages {
-		role := fromAIRoleToOpenAIRole(m.Role)
-		switch role {
-		case goopenai.ChatMessageRoleUser:
-			var multiContent []goopenai.ChatMessagePart
-			for _, part := range m.Content {
-				p, err := toOpenAiTextAndMedia(part)
-				if err != nil {
-					return nil, err
-				}
-				multiContent = append(multiContent, p)
-			}
-			msgs = append(msgs, goopenai.ChatCompletionMessage{
-				Role:         role,
-				MultiContent: multiContent,
-			})
-		case goopenai.ChatMessageRoleSystem:
-			msgs = append(msgs, goopenai.ChatCompletionMessage{
-				Role:    role,
-				Content: m.Content[0].Text,
-			})
-		case goopenai.ChatMessageRoleAssistant:
-			var toolCalls []goopenai.ToolCall
-			for _, part := range m.Content {
-				if !part.IsToolRequest() {
-					continue
-				}
-				toolCalls = append(toolCalls, goopenai.ToolCall{
-					ID:   part.ToolRequest.Name,
-					Type: goopenai.ToolTypeFunction,
-					Function: goopenai.FunctionCall{
-						Name:      part.ToolRequest.Name,
-						Arguments: mapToJSONString(part.ToolRequest.Input),
-					},
-				})
-			}
-			if len(toolCalls) > 0 {
-				msgs = append(msgs, goopenai.ChatCompletionMessage{
-					Role:      role,
-					ToolCalls: toolCalls,
-				})
-			} else {
-				msgs = append(msgs, goopenai.ChatCompletionMessage{
-					Role:    role,
-					Content: m.Content[0].Text,
-				})
-			}
-		case goopenai.ChatMessageRoleTool:
-			for _, part := range m.Content {
-				msgs = append(msgs, goopenai.ChatCompletionMessage{
-					Role:       role,
-					ToolCallID: part.ToolResponse.Name,
-					Content:    mapToJSONString(part.ToolResponse.Output),
-					Name:       part.ToolResponse.Name,
-				})
-			}
-		default:
-			return nil, fmt.Errorf("Unknown OpenAI Role %s", role)
-		}
-	}
-	return msgs, nil
-}
-
-func toOpenAiTextAndMedia(part *ai.Part) (goopenai.ChatMessagePart, error) {
-	switch {
-	case part.IsText():
-		return goopenai.ChatMessagePart{
-			Type: goopenai.ChatMessagePartTypeText,
-			Text: part.Text,
-		}, nil
-	case part.IsMedia():
-		return goopenai.ChatMessagePart{
-			Type: goopenai.ChatMessagePartTypeImageURL,
-			ImageURL: &goopenai.ChatMessageImageURL{
-				URL:    part.Text,
-				Detail: goopenai.ImageURLDetailAuto,
-			},
-		}, nil
-	default:
-		return goopenai.ChatMessagePart{}, fmt.Errorf("unknown part type in a request")
-	}
-}
-
-func convertTools(inTools []*ai.ToolDefinition) ([]goopenai.Tool, error) {
-	var outTools []goopenai.Tool
-	for _, t := range inTools {
-		parameters, err := mapToJSONRawMessage(t.InputSchema)
-		if err != nil {
-			return nil, err
-		}
-		fd := &goopenai.FunctionDefinition{
-			Name:        t.Name,
-			Description: t.Description,
-			Parameters:  parameters,
-		}
-		outTool := goopenai.Tool{
-			Type:     goopenai.ToolTypeFunction,
-			Function: fd,
-		}
-		outTools = append(outTools, outTool)
-	}
-	return outTools, nil
-}
-
-// Translate from a goopenai.ChatCompletionResponse to a ai.GenerateResponse.
-func translateResponse(resp goopenai.ChatCompletionResponse, jsonMode bool) *ai.GenerateResponse {
-	r := &ai.GenerateResponse{}
-
-	for _, c := range resp.Choices {
-		r.Candidates = append(r.Candidates, translateCandidate(c, jsonMode))
-	}
-
-	r.Usage = &ai.GenerationUsage{
-		InputTokens:  resp.Usage.PromptTokens,
-		OutputTokens: resp.Usage.CompletionTokens,
-		TotalTokens:  resp.Usage.TotalTokens,
-	}
-	r.Custom = resp
-	return r
-}
-
-func fromAIRoleToOpenAIRole(aiRole ai.Role) string {
-	switch aiRole {
-	case ai.RoleUser:
-		return goopenai.ChatMessageRoleUser
-	case ai.RoleSystem:
-		return goopenai.ChatMessageRoleSystem
-	case ai.RoleModel:
-		return goopenai.ChatMessageRoleAssistant
-	case ai.RoleTool:
-		return goopenai.ChatMessageRoleTool
-	default:
-		panic(fmt.Sprintf("Unknown ai.Role: %s", aiRole))
-	}
-}
-
-// translateCandidate translates from a goopenai.ChatCompletionChoice to an ai.Candidate.
-func translateCandidate(choice goopenai.ChatCompletionChoice, jsonMode bool) *ai.Candidate {
-	c := &ai.Candidate{
-		Index: choice.Index,
-	}
-	switch choice.FinishReason {
-	case goopenai.FinishReasonStop, goopenai.FinishReasonToolCalls:
-		c.FinishReason = ai.FinishReasonStop
-	case goopenai.FinishReasonLength:
-		c.FinishReason = ai.FinishReasonLength
-	case goopenai.FinishReasonContentFilter:
-		c.FinishReason = ai.FinishReasonBlocked
-	case goopenai.FinishReasonFunctionCall:
-		c.FinishReason = ai.FinishReasonOther
-	case goopenai.FinishReasonNull:
-		c.FinishReason = ai.FinishReasonUnknown
-	default:
-		c.FinishReason = ai.FinishReasonUnknown
-	}
-	m := &ai.Message{
-		Role: ai.RoleModel,
-	}
-
-	// handle tool calls
-	var toolRequestParts []*ai.Part
-	for _, toolCall := range choice.Message.ToolCalls {
-		toolRequestParts = append(toolRequestParts, ai.NewToolRequestPart(&ai.ToolRequest{
-			Name:  toolCall.Function.Name,
-			Input: jsonStringToMap(toolCall.Function.Arguments),
-		}))
-	}
-	if len(toolRequestParts) > 0 {
-		m.Content = toolRequestParts
-		c.Message = m
-		return c
-	}
-
-	if jsonMode {
-		m.Content = append(m.Content, ai.NewDataPart(choice.Message.Content))
-	} else {
-		m.Content = append(m.Content, ai.NewTextPart(choice.Message.Content))
-	}
-
-	c.Message = m
-	return c
-}
-
-func jsonStringToMap(jsonString string) map[string]any {
-	var result map[string]any
-	if err := json.Unmarshal([]byte(jsonString), &result); err != nil {
-		panic(fmt.Errorf("unmarshal failed to parse json string %s: %w", jsonString, err))
-	}
-	return result
-}
-
-func mapToJSONString(data map[string]any) string {
-	jsonBytes, err := json.Marshal(data)
-	if err != nil {
-		panic(fmt.Errorf("failed to marshal map to JSON string: data, %#v %w", data, err))
-	}
-	return string(jsonBytes)
-}
-
-func mapToJSONRawMessage(data map[string]any) (json.RawMessage, error) {
-	jsonBytes, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal map to JSON string: data, %#v %w", data, err)
-	}
-	return json.RawMessage(jsonBytes), nil
-}
-
-// func mapToJSONSchema(data map[string]any) (*goopenai.ChatCompletionResponseFormatJSONSchema, error) {
-// 	jsonData, err := json.Marshal(data)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	jsonSchema := &goopenai.ChatCompletionResponseFormatJSONSchema{}
-// 	if err := json.Unmarshal(jsonData, jsonSchema); err != nil {
-// 		return nil, fmt.Errorf("unmarshal failed to parse json string %s: %w", jsonData, err)
-// 	}
-// 	return jsonSchema, nil
-// }